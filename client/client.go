@@ -0,0 +1,340 @@
+// Package client implements a NGSI v2 HTTP client for context brokers such
+// as Orion.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/phoops/ngsiv2/model"
+)
+
+// ErrCanceled is returned when a request is aborted because its context was
+// canceled.
+var ErrCanceled = errors.New("ngsiv2: request canceled")
+
+// ErrDeadlineExceeded is returned when a request is aborted because its
+// context deadline (or the client's default timeout) elapsed.
+var ErrDeadlineExceeded = errors.New("ngsiv2: request deadline exceeded")
+
+// NgsiV2Client talks to a context broker's NGSI v2 API.
+type NgsiV2Client struct {
+	baseUrl        string
+	httpClient     *http.Client
+	defaultTimeout time.Duration
+}
+
+// ClientOption configures a NgsiV2Client at construction time.
+type ClientOption func(*NgsiV2Client) error
+
+// SetUrl sets the base URL the client will talk to.
+func SetUrl(u string) ClientOption {
+	return func(c *NgsiV2Client) error {
+		if _, err := url.Parse(u); err != nil {
+			return fmt.Errorf("invalid url '%s': %v", u, err)
+		}
+		c.baseUrl = u
+		return nil
+	}
+}
+
+// SetHTTPClient overrides the underlying http.Client used to issue requests.
+func SetHTTPClient(hc *http.Client) ClientOption {
+	return func(c *NgsiV2Client) error {
+		c.httpClient = hc
+		return nil
+	}
+}
+
+// SetDefaultTimeout makes every call that isn't given an explicit context
+// (the non-Ctx methods) run under context.WithTimeout(context.Background(), d).
+func SetDefaultTimeout(d time.Duration) ClientOption {
+	return func(c *NgsiV2Client) error {
+		c.defaultTimeout = d
+		return nil
+	}
+}
+
+// NewNgsiV2Client creates a new client applying the given options.
+func NewNgsiV2Client(opts ...ClientOption) (*NgsiV2Client, error) {
+	c := &NgsiV2Client{
+		httpClient: http.DefaultClient,
+	}
+	for _, opt := range opts {
+		if err := opt(c); err != nil {
+			return nil, err
+		}
+	}
+	if c.baseUrl == "" {
+		return nil, fmt.Errorf("no url provided")
+	}
+	return c, nil
+}
+
+// defaultContext returns the context to use when a method's non-Ctx variant
+// is called without one, honoring SetDefaultTimeout if it was set.
+func (c *NgsiV2Client) defaultContext() (context.Context, context.CancelFunc) {
+	if c.defaultTimeout > 0 {
+		return context.WithTimeout(context.Background(), c.defaultTimeout)
+	}
+	return context.Background(), func() {}
+}
+
+// translateCtxErr maps context errors to the package's exported sentinels so
+// callers can tell cancellation and deadline expiry apart from other
+// transport failures.
+func translateCtxErr(err error) error {
+	switch {
+	case errors.Is(err, context.Canceled):
+		return ErrCanceled
+	case errors.Is(err, context.DeadlineExceeded):
+		return ErrDeadlineExceeded
+	default:
+		return err
+	}
+}
+
+func (c *NgsiV2Client) newRequest(ctx context.Context, method, path string, query url.Values, body interface{}) (*http.Request, error) {
+	u := strings.TrimRight(c.baseUrl, "/") + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	var reqBody *bytes.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("unable to marshal request body: %v", err)
+		}
+		reqBody = bytes.NewReader(b)
+	} else {
+		reqBody = bytes.NewReader(nil)
+	}
+
+	req, err := http.NewRequest(method, u, reqBody)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	return req, nil
+}
+
+func (c *NgsiV2Client) do(req *http.Request) (*http.Response, error) {
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		if ctxErr := req.Context().Err(); ctxErr != nil {
+			return nil, translateCtxErr(ctxErr)
+		}
+		return nil, err
+	}
+	return res, nil
+}
+
+func readError(req *http.Request, res *http.Response) error {
+	defer res.Body.Close()
+	b, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		if ctxErr := req.Context().Err(); ctxErr != nil {
+			return translateCtxErr(ctxErr)
+		}
+	}
+	return fmt.Errorf("unexpected status code %d: %s", res.StatusCode, string(b))
+}
+
+// decodeOrCtxErr decodes r's JSON body into v. If the request's context was
+// canceled or timed out while the body was being read, it returns the
+// corresponding ErrCanceled/ErrDeadlineExceeded sentinel instead of the
+// generic decode error, so callers can tell the two apart regardless of
+// whether cancellation happened before or during the read.
+func decodeOrCtxErr(req *http.Request, r io.Reader, v interface{}, what string) error {
+	if err := json.NewDecoder(r).Decode(v); err != nil {
+		if ctxErr := req.Context().Err(); ctxErr != nil {
+			return translateCtxErr(ctxErr)
+		}
+		return fmt.Errorf("unable to decode %s: %v", what, err)
+	}
+	return nil
+}
+
+// APIResources lists the sub-resources exposed by the broker's `/v2` entry point.
+type APIResources struct {
+	EntitiesUrl      string `json:"entities_url"`
+	TypesUrl         string `json:"types_url"`
+	SubscriptionsUrl string `json:"subscriptions_url"`
+	RegistrationsUrl string `json:"registrations_url"`
+}
+
+// RetrieveAPIResources retrieves the entry point of the broker's NGSI v2 API.
+func (c *NgsiV2Client) RetrieveAPIResources() (*APIResources, error) {
+	ctx, cancel := c.defaultContext()
+	defer cancel()
+	return c.RetrieveAPIResourcesCtx(ctx)
+}
+
+// RetrieveAPIResourcesCtx is RetrieveAPIResources with an explicit context.
+func (c *NgsiV2Client) RetrieveAPIResourcesCtx(ctx context.Context) (*APIResources, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, "/v2", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, readError(req, res)
+	}
+
+	ar := &APIResources{}
+	if err := decodeOrCtxErr(req, res.Body, ar, "API resources"); err != nil {
+		return nil, err
+	}
+	return ar, nil
+}
+
+// BatchUpdate performs a `POST /v2/op/update` batch update operation.
+func (c *NgsiV2Client) BatchUpdate(bu *model.BatchUpdate) error {
+	ctx, cancel := c.defaultContext()
+	defer cancel()
+	return c.BatchUpdateCtx(ctx, bu)
+}
+
+// BatchUpdateCtx is BatchUpdate with an explicit context.
+func (c *NgsiV2Client) BatchUpdateCtx(ctx context.Context, bu *model.BatchUpdate) error {
+	req, err := c.newRequest(ctx, http.MethodPost, "/v2/op/update", nil, bu)
+	if err != nil {
+		return err
+	}
+
+	res, err := c.do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusNoContent {
+		return readError(req, res)
+	}
+	return nil
+}
+
+// RetrieveEntityOption configures a RetrieveEntity / RetrieveEntityCtx call.
+type RetrieveEntityOption func(url.Values)
+
+// RetrieveEntitySetType restricts the lookup to entities of the given type.
+func RetrieveEntitySetType(entityType string) RetrieveEntityOption {
+	return func(v url.Values) {
+		v.Set("type", entityType)
+	}
+}
+
+// RetrieveEntity performs a `GET /v2/entities/{id}` request.
+func (c *NgsiV2Client) RetrieveEntity(id string, opts ...RetrieveEntityOption) (*model.Entity, error) {
+	ctx, cancel := c.defaultContext()
+	defer cancel()
+	return c.RetrieveEntityCtx(ctx, id, opts...)
+}
+
+// RetrieveEntityCtx is RetrieveEntity with an explicit context.
+func (c *NgsiV2Client) RetrieveEntityCtx(ctx context.Context, id string, opts ...RetrieveEntityOption) (*model.Entity, error) {
+	query := url.Values{}
+	for _, opt := range opts {
+		opt(query)
+	}
+
+	req, err := c.newRequest(ctx, http.MethodGet, "/v2/entities/"+id, query, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, readError(req, res)
+	}
+
+	e := &model.Entity{}
+	if err := decodeOrCtxErr(req, res.Body, e, "entity"); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// ListEntitiesOption configures a ListEntities / ListEntitiesCtx call.
+type ListEntitiesOption func(url.Values)
+
+// ListEntitiesSetType restricts the listing to entities of the given type.
+func ListEntitiesSetType(entityType string) ListEntitiesOption {
+	return func(v url.Values) {
+		v.Set("type", entityType)
+	}
+}
+
+// ListEntitiesAddQueryStatement appends a query statement to the `q` filter.
+func ListEntitiesAddQueryStatement(qst model.QueryStatement) ListEntitiesOption {
+	return func(v url.Values) {
+		if existing := v.Get("q"); existing != "" {
+			v.Set("q", existing+";"+qst.String())
+		} else {
+			v.Set("q", qst.String())
+		}
+	}
+}
+
+// ListEntities performs a `GET /v2/entities` request.
+func (c *NgsiV2Client) ListEntities(opts ...ListEntitiesOption) ([]*model.Entity, error) {
+	ctx, cancel := c.defaultContext()
+	defer cancel()
+	return c.ListEntitiesCtx(ctx, opts...)
+}
+
+// ListEntitiesCtx is ListEntities with an explicit context.
+func (c *NgsiV2Client) ListEntitiesCtx(ctx context.Context, opts ...ListEntitiesOption) ([]*model.Entity, error) {
+	query := url.Values{}
+	for _, opt := range opts {
+		opt(query)
+	}
+
+	req, err := c.newRequest(ctx, http.MethodGet, "/v2/entities", query, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, readError(req, res)
+	}
+
+	var entities []*model.Entity
+	if err := decodeOrCtxErr(req, res.Body, &entities, "entities"); err != nil {
+		return nil, err
+	}
+	return entities, nil
+}
@@ -1,12 +1,15 @@
 package client_test
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/phoops/ngsiv2/client"
 	"github.com/phoops/ngsiv2/model"
@@ -180,3 +183,229 @@ func TestRetrieveEntities(t *testing.T) {
 		}
 	}
 }
+
+func TestRetrieveEntityCtxDeadlineExceeded(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				time.Sleep(50 * time.Millisecond)
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusOK)
+				fmt.Fprint(w, `{"id":"r1","type":"Room"}`)
+			}))
+	defer ts.Close()
+
+	cli, err := client.NewNgsiV2Client(client.SetUrl(ts.URL))
+	if err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+
+	if _, err := cli.RetrieveEntityCtx(ctx, "r1"); err != client.ErrDeadlineExceeded {
+		t.Fatalf("Expected ErrDeadlineExceeded, got '%v'", err)
+	}
+}
+
+func TestRetrieveEntityCtxCanceled(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				time.Sleep(50 * time.Millisecond)
+				w.WriteHeader(http.StatusOK)
+			}))
+	defer ts.Close()
+
+	cli, err := client.NewNgsiV2Client(client.SetUrl(ts.URL))
+	if err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(5 * time.Millisecond)
+		cancel()
+	}()
+
+	if _, err := cli.RetrieveEntityCtx(ctx, "r1"); err != client.ErrCanceled {
+		t.Fatalf("Expected ErrCanceled, got '%v'", err)
+	}
+}
+
+func TestListEntitiesGeoQuery(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Query().Get("georel") != "near;maxDistance:1000" {
+					t.Fatalf("Expected 'georel' value: 'near;maxDistance:1000', got '%s'", r.URL.Query().Get("georel"))
+				}
+				if r.URL.Query().Get("geometry") != "point" {
+					t.Fatalf("Expected 'geometry' value: 'point', got '%s'", r.URL.Query().Get("geometry"))
+				}
+				if r.URL.Query().Get("coords") != "40.4,-3.7" {
+					t.Fatalf("Expected 'coords' value: '40.4,-3.7', got '%s'", r.URL.Query().Get("coords"))
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusOK)
+				fmt.Fprint(w, `[]`)
+			}))
+	defer ts.Close()
+
+	cli, err := client.NewNgsiV2Client(client.SetUrl(ts.URL))
+	if err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+
+	if _, err := cli.ListEntities(
+		client.ListEntitiesSetGeoRel(model.GeoRelNear(-1, 1000)),
+		client.ListEntitiesSetGeometry("point"),
+		client.ListEntitiesSetCoords("40.4,-3.7")); err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+}
+
+func TestIterateEntities(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				offset := r.URL.Query().Get("offset")
+				w.Header().Set("Content-Type", "application/json")
+				if r.URL.Query().Get("options") == "count" {
+					w.Header().Set("Fiware-Total-Count", "3")
+				}
+				w.WriteHeader(http.StatusOK)
+				if offset == "0" {
+					fmt.Fprint(w, `[{"id":"r1","type":"Room"},{"id":"r2","type":"Room"}]`)
+				} else {
+					fmt.Fprint(w, `[{"id":"r3","type":"Room"}]`)
+				}
+			}))
+	defer ts.Close()
+
+	cli, err := client.NewNgsiV2Client(client.SetUrl(ts.URL))
+	if err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+
+	it := cli.IterateEntities(client.IterateEntitiesPageSize(2))
+	defer it.Close()
+
+	var ids []string
+	for {
+		e, err := it.Next(context.Background())
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Unexpected error: '%v'", err)
+		}
+		ids = append(ids, e.Id)
+	}
+
+	if len(ids) != 3 || ids[0] != "r1" || ids[1] != "r2" || ids[2] != "r3" {
+		t.Fatalf("Unexpected entities iterated: %v", ids)
+	}
+}
+
+func TestIterateEntitiesRequestsCountAsQueryParam(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Query().Get("options") != "count" {
+					t.Errorf("Expected 'options' value: 'count', got '%s'", r.URL.Query().Get("options"))
+				}
+				w.Header().Set("Content-Type", "application/json")
+				if r.URL.Query().Get("options") == "count" {
+					w.Header().Set("Fiware-Total-Count", "1")
+				}
+				w.WriteHeader(http.StatusOK)
+				fmt.Fprint(w, `[{"id":"r1","type":"Room"}]`)
+			}))
+	defer ts.Close()
+
+	cli, err := client.NewNgsiV2Client(client.SetUrl(ts.URL))
+	if err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+
+	it := cli.IterateEntities(client.IterateEntitiesPageSize(1))
+	defer it.Close()
+
+	var ids []string
+	for {
+		e, err := it.Next(context.Background())
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Unexpected error: '%v'", err)
+		}
+		ids = append(ids, e.Id)
+	}
+
+	if len(ids) != 1 || ids[0] != "r1" {
+		t.Fatalf("expected the iterator to stop once options=count reported total=1, got: %v", ids)
+	}
+}
+
+func TestIterateEntitiesParallelCloseAbortsInFlightFetch(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				time.Sleep(200 * time.Millisecond)
+				w.Header().Set("Content-Type", "application/json")
+				w.Header().Set("Fiware-Total-Count", "1")
+				w.WriteHeader(http.StatusOK)
+				fmt.Fprint(w, `[{"id":"r1","type":"Room"}]`)
+			}))
+	defer ts.Close()
+
+	cli, err := client.NewNgsiV2Client(client.SetUrl(ts.URL))
+	if err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+
+	it := cli.IterateEntities(client.IterateEntitiesParallel(1))
+
+	time.Sleep(10 * time.Millisecond)
+	it.Close()
+
+	done := make(chan struct{})
+	go func() {
+		it.Next(context.Background())
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(100 * time.Millisecond):
+		t.Fatal("Next did not return promptly after Close aborted the in-flight fetch")
+	}
+}
+
+func TestListEntitiesCtxDeadlineExceededMidBody(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusOK)
+				fmt.Fprint(w, `[{"id":"r1","type":"Room"`)
+				w.(http.Flusher).Flush()
+				time.Sleep(50 * time.Millisecond)
+				fmt.Fprint(w, `}]`)
+			}))
+	defer ts.Close()
+
+	cli, err := client.NewNgsiV2Client(client.SetUrl(ts.URL))
+	if err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if _, err := cli.ListEntitiesCtx(ctx); err != client.ErrDeadlineExceeded {
+		t.Fatalf("Expected ErrDeadlineExceeded, got '%v'", err)
+	}
+}
@@ -0,0 +1,30 @@
+package client
+
+import (
+	"net/url"
+
+	"github.com/phoops/ngsiv2/model"
+)
+
+// ListEntitiesSetGeoRel sets the `georel` parameter of a geo-query.
+func ListEntitiesSetGeoRel(georel model.GeoRel) ListEntitiesOption {
+	return func(v url.Values) {
+		v.Set("georel", georel.String())
+	}
+}
+
+// ListEntitiesSetGeometry sets the `geometry` parameter of a geo-query, e.g.
+// "point", "line", "polygon" or "box".
+func ListEntitiesSetGeometry(geometry string) ListEntitiesOption {
+	return func(v url.Values) {
+		v.Set("geometry", geometry)
+	}
+}
+
+// ListEntitiesSetCoords sets the `coords` parameter of a geo-query, e.g.
+// "40.4,-3.7".
+func ListEntitiesSetCoords(coords string) ListEntitiesOption {
+	return func(v url.Values) {
+		v.Set("coords", coords)
+	}
+}
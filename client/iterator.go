@@ -0,0 +1,239 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+
+	"github.com/phoops/ngsiv2/model"
+)
+
+const defaultIteratorPageSize = 20
+
+// IterateEntitiesOption configures an EntityIterator.
+type IterateEntitiesOption func(*iterateConfig)
+
+type iterateConfig struct {
+	listOpts []ListEntitiesOption
+	pageSize int
+	workers  int
+}
+
+// IterateEntitiesFilter applies ListEntities options (type, query statements,
+// geo-query, ...) to every page the iterator fetches.
+func IterateEntitiesFilter(opts ...ListEntitiesOption) IterateEntitiesOption {
+	return func(c *iterateConfig) {
+		c.listOpts = append(c.listOpts, opts...)
+	}
+}
+
+// IterateEntitiesPageSize sets how many entities are requested per page.
+// Defaults to 20.
+func IterateEntitiesPageSize(n int) IterateEntitiesOption {
+	return func(c *iterateConfig) {
+		c.pageSize = n
+	}
+}
+
+// IterateEntitiesParallel makes the iterator prefetch pages ahead of the one
+// currently being consumed instead of fetching the next page synchronously
+// from Next, buffering up to workers pages so the caller's processing of the
+// current page overlaps with the single background fetcher retrieving the
+// next one.
+func IterateEntitiesParallel(workers int) IterateEntitiesOption {
+	return func(c *iterateConfig) {
+		c.workers = workers
+	}
+}
+
+func listEntitiesSetOffset(offset int) ListEntitiesOption {
+	return func(v url.Values) {
+		v.Set("offset", strconv.Itoa(offset))
+	}
+}
+
+func listEntitiesSetLimit(limit int) ListEntitiesOption {
+	return func(v url.Values) {
+		v.Set("limit", strconv.Itoa(limit))
+	}
+}
+
+// listEntitiesCountCtx is ListEntitiesCtx plus the options=count query
+// parameter, also returning the broker-reported total number of matching
+// entities from the Fiware-Total-Count response header.
+func (c *NgsiV2Client) listEntitiesCountCtx(ctx context.Context, opts ...ListEntitiesOption) ([]*model.Entity, int, error) {
+	query := url.Values{}
+	for _, opt := range opts {
+		opt(query)
+	}
+	query.Set("options", "count")
+
+	req, err := c.newRequest(ctx, http.MethodGet, "/v2/entities", query, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	res, err := c.do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, 0, readError(req, res)
+	}
+
+	var entities []*model.Entity
+	if err := decodeOrCtxErr(req, res.Body, &entities, "entities"); err != nil {
+		return nil, 0, err
+	}
+
+	total, _ := strconv.Atoi(res.Header.Get("Fiware-Total-Count"))
+	return entities, total, nil
+}
+
+type page struct {
+	entities []*model.Entity
+	err      error
+}
+
+// EntityIterator streams the results of ListEntities page by page, advancing
+// `offset` automatically and stopping once the broker-reported
+// Fiware-Total-Count has been reached, so callers can consume arbitrarily
+// large result sets without loading them all into memory.
+type EntityIterator struct {
+	cli *NgsiV2Client
+	cfg iterateConfig
+
+	buf    []*model.Entity
+	bufIdx int
+	err    error
+
+	offset    int
+	total     int
+	haveTotal bool
+
+	pages  chan page
+	cancel context.CancelFunc
+	once   sync.Once
+}
+
+// IterateEntities creates an EntityIterator over all entities matching opts.
+func (c *NgsiV2Client) IterateEntities(opts ...IterateEntitiesOption) *EntityIterator {
+	cfg := iterateConfig{pageSize: defaultIteratorPageSize}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	it := &EntityIterator{cli: c, cfg: cfg}
+	if cfg.workers > 0 {
+		ctx, cancel := context.WithCancel(context.Background())
+		it.cancel = cancel
+		it.pages = make(chan page, cfg.workers)
+		go it.prefetchLoop(ctx)
+	}
+	return it
+}
+
+func (it *EntityIterator) fetchPage(ctx context.Context) ([]*model.Entity, error) {
+	opts := append(append([]ListEntitiesOption{}, it.cfg.listOpts...),
+		listEntitiesSetOffset(it.offset),
+		listEntitiesSetLimit(it.cfg.pageSize))
+
+	entities, total, err := it.cli.listEntitiesCountCtx(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	it.haveTotal = true
+	it.total = total
+	it.offset += len(entities)
+	return entities, nil
+}
+
+func (it *EntityIterator) exhausted() bool {
+	return it.haveTotal && it.offset >= it.total
+}
+
+// prefetchLoop fetches pages ahead of consumption, using ctx for every
+// request so that canceling it (via Close) both aborts any in-flight fetch
+// and stops scheduling further ones.
+func (it *EntityIterator) prefetchLoop(ctx context.Context) {
+	defer close(it.pages)
+	for {
+		entities, err := it.fetchPage(ctx)
+		select {
+		case it.pages <- page{entities: entities, err: err}:
+		case <-ctx.Done():
+			return
+		}
+		if err != nil || len(entities) == 0 || it.exhausted() {
+			return
+		}
+	}
+}
+
+// Next returns the next entity, fetching additional pages as needed. It
+// returns io.EOF once every matching entity has been returned.
+func (it *EntityIterator) Next(ctx context.Context) (*model.Entity, error) {
+	for it.bufIdx >= len(it.buf) {
+		if it.err != nil {
+			return nil, it.err
+		}
+
+		var entities []*model.Entity
+		var err error
+
+		if it.pages != nil {
+			select {
+			case p, ok := <-it.pages:
+				if !ok {
+					return nil, io.EOF
+				}
+				entities, err = p.entities, p.err
+			case <-ctx.Done():
+				return nil, translateCtxErr(ctx.Err())
+			}
+		} else {
+			if it.exhausted() {
+				return nil, io.EOF
+			}
+			entities, err = it.fetchPage(ctx)
+		}
+
+		if err != nil {
+			it.err = err
+			return nil, err
+		}
+		if len(entities) == 0 {
+			return nil, io.EOF
+		}
+
+		it.buf = entities
+		it.bufIdx = 0
+	}
+
+	e := it.buf[it.bufIdx]
+	it.bufIdx++
+	return e, nil
+}
+
+// Err returns the first non-EOF error encountered while fetching a page, if
+// any. In parallel mode, a Next call aborted by its own ctx while waiting for
+// a prefetched page is not recorded here.
+func (it *EntityIterator) Err() error {
+	return it.err
+}
+
+// Close cancels the context used by any in-flight or future prefetch
+// request, aborting it rather than letting it run to completion. It is a
+// no-op in non-parallel mode, and safe to call more than once.
+func (it *EntityIterator) Close() error {
+	if it.cancel != nil {
+		it.once.Do(it.cancel)
+	}
+	return nil
+}
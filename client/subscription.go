@@ -0,0 +1,159 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/phoops/ngsiv2/model"
+)
+
+// CreateSubscription performs a `POST /v2/subscriptions` request and returns
+// the broker-assigned subscription id.
+func (c *NgsiV2Client) CreateSubscription(sub *model.Subscription) (string, error) {
+	ctx, cancel := c.defaultContext()
+	defer cancel()
+	return c.CreateSubscriptionCtx(ctx, sub)
+}
+
+// CreateSubscriptionCtx is CreateSubscription with an explicit context.
+func (c *NgsiV2Client) CreateSubscriptionCtx(ctx context.Context, sub *model.Subscription) (string, error) {
+	req, err := c.newRequest(ctx, http.MethodPost, "/v2/subscriptions", nil, sub)
+	if err != nil {
+		return "", err
+	}
+
+	res, err := c.do(req)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusCreated {
+		return "", readError(req, res)
+	}
+
+	return subscriptionIdFromLocation(res.Header.Get("Location")), nil
+}
+
+func subscriptionIdFromLocation(location string) string {
+	idx := strings.LastIndex(location, "/")
+	if idx < 0 {
+		return location
+	}
+	return location[idx+1:]
+}
+
+// ListSubscriptions performs a `GET /v2/subscriptions` request.
+func (c *NgsiV2Client) ListSubscriptions() ([]*model.Subscription, error) {
+	ctx, cancel := c.defaultContext()
+	defer cancel()
+	return c.ListSubscriptionsCtx(ctx)
+}
+
+// ListSubscriptionsCtx is ListSubscriptions with an explicit context.
+func (c *NgsiV2Client) ListSubscriptionsCtx(ctx context.Context) ([]*model.Subscription, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, "/v2/subscriptions", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, readError(req, res)
+	}
+
+	var subs []*model.Subscription
+	if err := decodeOrCtxErr(req, res.Body, &subs, "subscriptions"); err != nil {
+		return nil, err
+	}
+	return subs, nil
+}
+
+// RetrieveSubscription performs a `GET /v2/subscriptions/{id}` request.
+func (c *NgsiV2Client) RetrieveSubscription(id string) (*model.Subscription, error) {
+	ctx, cancel := c.defaultContext()
+	defer cancel()
+	return c.RetrieveSubscriptionCtx(ctx, id)
+}
+
+// RetrieveSubscriptionCtx is RetrieveSubscription with an explicit context.
+func (c *NgsiV2Client) RetrieveSubscriptionCtx(ctx context.Context, id string) (*model.Subscription, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, "/v2/subscriptions/"+id, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, readError(req, res)
+	}
+
+	sub := &model.Subscription{}
+	if err := decodeOrCtxErr(req, res.Body, sub, "subscription"); err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+// UpdateSubscription performs a `PATCH /v2/subscriptions/{id}` request.
+func (c *NgsiV2Client) UpdateSubscription(id string, sub *model.Subscription) error {
+	ctx, cancel := c.defaultContext()
+	defer cancel()
+	return c.UpdateSubscriptionCtx(ctx, id, sub)
+}
+
+// UpdateSubscriptionCtx is UpdateSubscription with an explicit context.
+func (c *NgsiV2Client) UpdateSubscriptionCtx(ctx context.Context, id string, sub *model.Subscription) error {
+	req, err := c.newRequest(ctx, http.MethodPatch, "/v2/subscriptions/"+id, nil, sub)
+	if err != nil {
+		return err
+	}
+
+	res, err := c.do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusNoContent {
+		return readError(req, res)
+	}
+	return nil
+}
+
+// DeleteSubscription performs a `DELETE /v2/subscriptions/{id}` request.
+func (c *NgsiV2Client) DeleteSubscription(id string) error {
+	ctx, cancel := c.defaultContext()
+	defer cancel()
+	return c.DeleteSubscriptionCtx(ctx, id)
+}
+
+// DeleteSubscriptionCtx is DeleteSubscription with an explicit context.
+func (c *NgsiV2Client) DeleteSubscriptionCtx(ctx context.Context, id string) error {
+	req, err := c.newRequest(ctx, http.MethodDelete, "/v2/subscriptions/"+id, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	res, err := c.do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusNoContent {
+		return readError(req, res)
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package client_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/phoops/ngsiv2/client"
+	"github.com/phoops/ngsiv2/model"
+)
+
+func TestCreateSubscription(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("Content-Type") != "application/json" {
+					t.Fatal("Missing application/json content-type header")
+				}
+				if b, err := ioutil.ReadAll(r.Body); err != nil {
+					t.Fatalf("Unexpected error: '%v'", err)
+				} else if !strings.Contains(string(b), "subject") {
+					t.Fatal("Request doesn't contain subject")
+				}
+				w.Header().Set("Location", "/v2/subscriptions/sub1")
+				w.WriteHeader(http.StatusCreated)
+			}))
+	defer ts.Close()
+
+	cli, err := client.NewNgsiV2Client(client.SetUrl(ts.URL))
+	if err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+
+	sub := model.NewSubscription("Room", "http://receiver/notify")
+	id, err := cli.CreateSubscription(sub)
+	if err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+	if id != "sub1" {
+		t.Fatalf("Expected 'sub1', got '%s'", id)
+	}
+}
+
+func TestCreateSubscriptionBadRequest(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprint(w, `{"error":"BadRequest","description":"invalid subscription"}`)
+			}))
+	defer ts.Close()
+
+	cli, err := client.NewNgsiV2Client(client.SetUrl(ts.URL))
+	if err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+
+	if _, err := cli.CreateSubscription(model.NewSubscription("Room", "http://receiver/notify")); err == nil {
+		t.Fatal("Expected an error")
+	}
+}
+
+func TestListSubscriptions(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusOK)
+				fmt.Fprint(w, `[{"id":"sub1","subject":{"entities":[{"type":"Room"}],"condition":{}},"notification":{"http":{"url":"http://receiver/notify"}}}]`)
+			}))
+	defer ts.Close()
+
+	cli, err := client.NewNgsiV2Client(client.SetUrl(ts.URL))
+	if err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+
+	subs, err := cli.ListSubscriptions()
+	if err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+	if len(subs) != 1 || subs[0].Id != "sub1" || subs[0].Notification.Http.Url != "http://receiver/notify" {
+		t.Fatalf("Invalid subscriptions retrieved: %+v", subs)
+	}
+}
+
+func TestRetrieveSubscription(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				if !strings.HasSuffix(r.URL.Path, "/sub1") {
+					t.Fatal("Expected 'sub1' as id")
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusOK)
+				fmt.Fprint(w, `{"id":"sub1","subject":{"entities":[{"type":"Room"}],"condition":{}},"notification":{"http":{"url":"http://receiver/notify"}}}`)
+			}))
+	defer ts.Close()
+
+	cli, err := client.NewNgsiV2Client(client.SetUrl(ts.URL))
+	if err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+
+	sub, err := cli.RetrieveSubscription("sub1")
+	if err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+	if sub.Id != "sub1" || sub.Subject.Entities[0].Type != "Room" {
+		t.Fatalf("Invalid subscription retrieved: %+v", sub)
+	}
+}
+
+func TestUpdateSubscription(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPatch {
+					t.Fatalf("Expected PATCH, got %s", r.Method)
+				}
+				if !strings.HasSuffix(r.URL.Path, "/sub1") {
+					t.Fatal("Expected 'sub1' as id")
+				}
+				w.WriteHeader(http.StatusNoContent)
+			}))
+	defer ts.Close()
+
+	cli, err := client.NewNgsiV2Client(client.SetUrl(ts.URL))
+	if err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+
+	if err := cli.UpdateSubscription("sub1", &model.Subscription{Description: "updated"}); err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+}
+
+func TestDeleteSubscription(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodDelete {
+					t.Fatalf("Expected DELETE, got %s", r.Method)
+				}
+				if !strings.HasSuffix(r.URL.Path, "/sub1") {
+					t.Fatal("Expected 'sub1' as id")
+				}
+				w.WriteHeader(http.StatusNoContent)
+			}))
+	defer ts.Close()
+
+	cli, err := client.NewNgsiV2Client(client.SetUrl(ts.URL))
+	if err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+
+	if err := cli.DeleteSubscription("sub1"); err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+}
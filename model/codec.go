@@ -0,0 +1,150 @@
+package model
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// AttributeCodec converts an attribute's value between its raw JSON
+// representation and a strongly-typed Go value, for a single AttributeType.
+type AttributeCodec interface {
+	// Type is the AttributeType this codec handles.
+	Type() AttributeType
+	// Marshal converts a Go value into the raw value stored in Attribute.Value.
+	Marshal(value interface{}) (interface{}, error)
+	// Unmarshal converts Attribute.Value into a Go value.
+	Unmarshal(raw interface{}) (interface{}, error)
+}
+
+var (
+	codecsMu sync.RWMutex
+	codecs   = map[AttributeType]AttributeCodec{}
+)
+
+// RegisterCodec registers the codec used for its AttributeType, replacing
+// any codec previously registered for it. It is safe to call concurrently
+// with GetTyped/SetTyped, e.g. to register a custom codec after startup
+// while entities are already being decoded.
+func RegisterCodec(codec AttributeCodec) {
+	codecsMu.Lock()
+	defer codecsMu.Unlock()
+	codecs[codec.Type()] = codec
+}
+
+func codecFor(t AttributeType) (AttributeCodec, bool) {
+	codecsMu.RLock()
+	defer codecsMu.RUnlock()
+	c, ok := codecs[t]
+	return c, ok
+}
+
+func init() {
+	RegisterCodec(dateTimeCodec{})
+	RegisterCodec(percentageCodec{})
+	RegisterCodec(structuredValueCodec{})
+	RegisterCodec(geoJsonCodec{})
+}
+
+// dateTimeLayout is the ISO-8601 format NGSI v2 requires for DateTime
+// attributes: UTC, millisecond precision, "Z" suffix. Used for Marshal; other
+// NGSI-valid fractional precisions are accepted on Unmarshal, see
+// dateTimeUnmarshalLayouts.
+const dateTimeLayout = "2006-01-02T15:04:05.000Z"
+
+// dateTimeUnmarshalLayouts are tried in order when parsing a DateTime value,
+// since brokers and other clients may emit no fraction, or a precision other
+// than the milliseconds this package writes.
+var dateTimeUnmarshalLayouts = []string{
+	"2006-01-02T15:04:05.999999999Z",
+	"2006-01-02T15:04:05Z",
+}
+
+type dateTimeCodec struct{}
+
+func (dateTimeCodec) Type() AttributeType { return DateTimeType }
+
+func (dateTimeCodec) Marshal(value interface{}) (interface{}, error) {
+	t, ok := value.(time.Time)
+	if !ok {
+		return nil, fmt.Errorf("DateTime value must be a time.Time, got %T", value)
+	}
+	return t.UTC().Format(dateTimeLayout), nil
+}
+
+func (dateTimeCodec) Unmarshal(raw interface{}) (interface{}, error) {
+	s, ok := raw.(string)
+	if !ok {
+		return nil, fmt.Errorf("DateTime value must be a string, got %T", raw)
+	}
+	var t time.Time
+	var err error
+	for _, layout := range dateTimeUnmarshalLayouts {
+		if t, err = time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return nil, fmt.Errorf("invalid DateTime value '%s': %v", s, err)
+}
+
+// percentageCodec marshals/unmarshals Percentage attributes to/from float64.
+type percentageCodec struct{}
+
+func (percentageCodec) Type() AttributeType { return PercentageType }
+
+func (percentageCodec) Marshal(value interface{}) (interface{}, error) {
+	f, ok := value.(float64)
+	if !ok {
+		return nil, fmt.Errorf("Percentage value must be a float64, got %T", value)
+	}
+	return f, nil
+}
+
+func (percentageCodec) Unmarshal(raw interface{}) (interface{}, error) {
+	f, ok := raw.(float64)
+	if !ok {
+		return nil, fmt.Errorf("Percentage value must be a number, got %T", raw)
+	}
+	return f, nil
+}
+
+// structuredValueCodec marshals/unmarshals StructuredValue attributes,
+// passing arbitrary maps and slices through unchanged.
+type structuredValueCodec struct{}
+
+func (structuredValueCodec) Type() AttributeType { return StructuredValueType }
+
+func (structuredValueCodec) Marshal(value interface{}) (interface{}, error) {
+	switch value.(type) {
+	case map[string]interface{}, []interface{}:
+		return value, nil
+	default:
+		return nil, fmt.Errorf("StructuredValue value must be a map or slice, got %T", value)
+	}
+}
+
+func (structuredValueCodec) Unmarshal(raw interface{}) (interface{}, error) {
+	return raw, nil
+}
+
+// geoJsonCodec marshals/unmarshals geo:json attributes, passing the raw
+// GeoJSON object through unchanged.
+type geoJsonCodec struct{}
+
+func (geoJsonCodec) Type() AttributeType { return GeoJsonType }
+
+func (geoJsonCodec) Marshal(value interface{}) (interface{}, error) {
+	m, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("geo:json value must be a map, got %T", value)
+	}
+	return m, nil
+}
+
+func (geoJsonCodec) Unmarshal(raw interface{}) (interface{}, error) {
+	m, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("geo:json value must be an object, got %T", raw)
+	}
+	return m, nil
+}
@@ -0,0 +1,202 @@
+package model_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/phoops/ngsiv2/model"
+)
+
+func TestSetAndGetAsDateTime(t *testing.T) {
+	e := model.NewEntity("r1", "Room")
+
+	ts := time.Date(2020, time.March, 1, 12, 30, 0, 0, time.UTC)
+	if err := e.SetAttributeAsDateTime("observedAt", ts); err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+
+	attr, err := e.GetAttribute("observedAt")
+	if err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+	if attr.Type != model.DateTimeType {
+		t.Fatalf("Expected DateTimeType, got %s", attr.Type)
+	}
+	if attr.Value != "2020-03-01T12:30:00.000Z" {
+		t.Fatalf("Unexpected raw value: %v", attr.Value)
+	}
+
+	got, err := attr.GetAsDateTime()
+	if err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+	if !got.Equal(ts) {
+		t.Fatalf("Expected '%v', got '%v'", ts, got)
+	}
+}
+
+func TestGetAsDateTimeVariableFractionalPrecision(t *testing.T) {
+	cases := []struct {
+		name  string
+		raw   string
+		wantT time.Time
+	}{
+		{"no fraction", "2026-07-26T10:00:00Z", time.Date(2026, time.July, 26, 10, 0, 0, 0, time.UTC)},
+		{"two-digit fraction", "2026-07-26T10:00:00.12Z", time.Date(2026, time.July, 26, 10, 0, 0, 120000000, time.UTC)},
+		{"microsecond fraction", "2026-07-26T10:00:00.123456Z", time.Date(2026, time.July, 26, 10, 0, 0, 123456000, time.UTC)},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			e := model.NewEntity("r1", "Room")
+			attr := &model.Attribute{}
+			attr.Type = model.DateTimeType
+			attr.Value = c.raw
+			e.Attributes["observedAt"] = attr
+
+			attr, err := e.GetAttribute("observedAt")
+			if err != nil {
+				t.Fatalf("Unexpected error: '%v'", err)
+			}
+
+			got, err := attr.GetAsDateTime()
+			if err != nil {
+				t.Fatalf("Unexpected error: '%v'", err)
+			}
+			if !got.Equal(c.wantT) {
+				t.Fatalf("Expected '%v', got '%v'", c.wantT, got)
+			}
+		})
+	}
+}
+
+func TestGetAsDateTimeWrongType(t *testing.T) {
+	e := model.NewEntity("r1", "Room")
+	e.SetAttributeAsString("name", "kitchen")
+
+	attr, _ := e.GetAttribute("name")
+	if _, err := attr.GetAsDateTime(); err == nil {
+		t.Fatal("Expected an error")
+	}
+}
+
+func TestSetTypedWithoutRegisteredCodec(t *testing.T) {
+	e := model.NewEntity("r1", "Room")
+
+	if err := e.SetTyped("name", model.StringType, "kitchen"); err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+
+	attr, err := e.GetAttribute("name")
+	if err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+	if attr.Value != "kitchen" {
+		t.Fatalf("Expected raw value to pass through unchanged, got %v", attr.Value)
+	}
+
+	got, err := attr.GetTyped()
+	if err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+	if got != "kitchen" {
+		t.Fatalf("Expected 'kitchen', got '%v'", got)
+	}
+}
+
+func TestDateTimeCodecMalformedValue(t *testing.T) {
+	e := model.NewEntity("r1", "Room")
+	if err := e.SetTyped("observedAt", model.DateTimeType, "not-a-date"); err == nil {
+		t.Fatal("Expected an error marshaling a non-time.Time value")
+	}
+
+	attr := &model.Attribute{}
+	attr.Type = model.DateTimeType
+	attr.Value = "not-a-date"
+	if _, err := attr.GetAsDateTime(); err == nil {
+		t.Fatal("Expected an error parsing a malformed DateTime value")
+	}
+}
+
+func TestPercentageCodecRoundTrip(t *testing.T) {
+	e := model.NewEntity("r1", "Room")
+
+	if err := e.SetTyped("humidity", model.PercentageType, 42.5); err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+
+	attr, _ := e.GetAttribute("humidity")
+	got, err := attr.GetTyped()
+	if err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+	if got != 42.5 {
+		t.Fatalf("Expected 42.5, got %v", got)
+	}
+}
+
+func TestPercentageCodecWrongType(t *testing.T) {
+	e := model.NewEntity("r1", "Room")
+	if err := e.SetTyped("humidity", model.PercentageType, "42.5"); err == nil {
+		t.Fatal("Expected an error marshaling a non-float64 value")
+	}
+}
+
+func TestStructuredValueCodecRoundTrip(t *testing.T) {
+	e := model.NewEntity("r1", "Room")
+
+	value := map[string]interface{}{"a": 1.0, "b": "two"}
+	if err := e.SetTyped("config", model.StructuredValueType, value); err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+
+	attr, _ := e.GetAttribute("config")
+	got, err := attr.GetTyped()
+	if err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+	m, ok := got.(map[string]interface{})
+	if !ok || m["b"] != "two" {
+		t.Fatalf("Unexpected structured value: %v", got)
+	}
+}
+
+func TestStructuredValueCodecWrongType(t *testing.T) {
+	e := model.NewEntity("r1", "Room")
+	if err := e.SetTyped("config", model.StructuredValueType, 42); err == nil {
+		t.Fatal("Expected an error marshaling a value that isn't a map or slice")
+	}
+}
+
+func TestGeoJsonCodecRoundTrip(t *testing.T) {
+	e := model.NewEntity("r1", "Room")
+
+	value := map[string]interface{}{"type": "Point", "coordinates": []interface{}{-3.7, 40.4}}
+	if err := e.SetTyped("location", model.GeoJsonType, value); err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+
+	attr, _ := e.GetAttribute("location")
+	got, err := attr.GetTyped()
+	if err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+	m, ok := got.(map[string]interface{})
+	if !ok || m["type"] != "Point" {
+		t.Fatalf("Unexpected geo:json value: %v", got)
+	}
+}
+
+func TestGeoJsonCodecWrongType(t *testing.T) {
+	e := model.NewEntity("r1", "Room")
+	if err := e.SetTyped("location", model.GeoJsonType, "not-a-geojson-object"); err == nil {
+		t.Fatal("Expected an error marshaling a non-map value")
+	}
+
+	attr := &model.Attribute{}
+	attr.Type = model.GeoJsonType
+	attr.Value = "not-an-object"
+	if _, err := attr.GetTyped(); err == nil {
+		t.Fatal("Expected an error unmarshaling a non-object geo:json value")
+	}
+}
@@ -0,0 +1,86 @@
+package model
+
+import "fmt"
+
+const (
+	GeoPointType   AttributeType = "geo:point"
+	GeoLineType    AttributeType = "geo:line"
+	GeoPolygonType AttributeType = "geo:polygon"
+	GeoBoxType     AttributeType = "geo:box"
+	GeoJsonType    AttributeType = "geo:json"
+)
+
+// GeoPoint is a WGS84 latitude/longitude pair, the Go-side representation of
+// a geo:point attribute value.
+type GeoPoint struct {
+	Latitude  float64
+	Longitude float64
+}
+
+// SetAttributeAsGeoPoint sets attribute name to a geo:point value.
+func (e *Entity) SetAttributeAsGeoPoint(name string, lat, lon float64) {
+	e.Attributes[name] = &Attribute{
+		typeValue: typeValue{
+			Type:  GeoPointType,
+			Value: fmt.Sprintf("%v, %v", lat, lon),
+		},
+	}
+}
+
+// GetAsGeoPoint returns the geo:point value of the attribute.
+func (a *Attribute) GetAsGeoPoint() (GeoPoint, error) {
+	if a.Type != GeoPointType {
+		return GeoPoint{}, fmt.Errorf("Attribute is not geo:point, but %s", a.Type)
+	}
+	s, ok := a.Value.(string)
+	if !ok {
+		return GeoPoint{}, fmt.Errorf("geo:point value is not a string: %v", a.Value)
+	}
+
+	var lat, lon float64
+	if _, err := fmt.Sscanf(s, "%f, %f", &lat, &lon); err != nil {
+		return GeoPoint{}, fmt.Errorf("invalid geo:point value '%s': %v", s, err)
+	}
+	return GeoPoint{Latitude: lat, Longitude: lon}, nil
+}
+
+// GeoRel is a `georel` query parameter value. Build it with the GeoRel*
+// functions rather than concatenating strings by hand.
+type GeoRel string
+
+func (g GeoRel) String() string {
+	return string(g)
+}
+
+// GeoRelCoveredBy requires the entity's geometry to be covered by the query geometry.
+func GeoRelCoveredBy() GeoRel {
+	return GeoRel("coveredBy")
+}
+
+// GeoRelIntersects requires the entity's geometry to intersect the query geometry.
+func GeoRelIntersects() GeoRel {
+	return GeoRel("intersects")
+}
+
+// GeoRelEquals requires the entity's geometry to equal the query geometry.
+func GeoRelEquals() GeoRel {
+	return GeoRel("equals")
+}
+
+// GeoRelDisjoint requires the entity's geometry to be disjoint from the query geometry.
+func GeoRelDisjoint() GeoRel {
+	return GeoRel("disjoint")
+}
+
+// GeoRelNear builds a `near` relation bounded by distance in meters, e.g.
+// `near;minDistance:10;maxDistance:1000`. Pass a negative bound to omit it.
+func GeoRelNear(minDistance, maxDistance int) GeoRel {
+	rel := "near"
+	if minDistance >= 0 {
+		rel += fmt.Sprintf(";minDistance:%d", minDistance)
+	}
+	if maxDistance >= 0 {
+		rel += fmt.Sprintf(";maxDistance:%d", maxDistance)
+	}
+	return GeoRel(rel)
+}
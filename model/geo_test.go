@@ -0,0 +1,69 @@
+package model_test
+
+import (
+	"testing"
+
+	"github.com/phoops/ngsiv2/model"
+)
+
+func TestSetAndGetAsGeoPoint(t *testing.T) {
+	e := model.NewEntity("r1", "Room")
+	e.SetAttributeAsGeoPoint("location", 40.4, -3.7)
+
+	attr, err := e.GetAttribute("location")
+	if err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+	if attr.Type != model.GeoPointType {
+		t.Fatalf("Expected GeoPointType, got %s", attr.Type)
+	}
+
+	p, err := attr.GetAsGeoPoint()
+	if err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+	if p.Latitude != 40.4 || p.Longitude != -3.7 {
+		t.Fatalf("Unexpected GeoPoint: %+v", p)
+	}
+}
+
+func TestGetAsGeoPointWrongType(t *testing.T) {
+	e := model.NewEntity("r1", "Room")
+	e.SetAttributeAsString("name", "kitchen")
+
+	attr, _ := e.GetAttribute("name")
+	if _, err := attr.GetAsGeoPoint(); err == nil {
+		t.Fatal("Expected an error")
+	}
+}
+
+func TestGetAsGeoPointMalformedValue(t *testing.T) {
+	attr := &model.Attribute{}
+	attr.Type = model.GeoPointType
+	attr.Value = "not-a-point"
+
+	if _, err := attr.GetAsGeoPoint(); err == nil {
+		t.Fatal("Expected an error parsing a malformed geo:point value")
+	}
+}
+
+func TestGeoRelBuilders(t *testing.T) {
+	cases := []struct {
+		rel      model.GeoRel
+		expected string
+	}{
+		{model.GeoRelCoveredBy(), "coveredBy"},
+		{model.GeoRelIntersects(), "intersects"},
+		{model.GeoRelEquals(), "equals"},
+		{model.GeoRelDisjoint(), "disjoint"},
+		{model.GeoRelNear(10, 1000), "near;minDistance:10;maxDistance:1000"},
+		{model.GeoRelNear(-1, 1000), "near;maxDistance:1000"},
+		{model.GeoRelNear(10, -1), "near;minDistance:10"},
+	}
+
+	for _, c := range cases {
+		if c.rel.String() != c.expected {
+			t.Errorf("Expected '%s', got '%s'", c.expected, c.rel.String())
+		}
+	}
+}
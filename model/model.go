@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"reflect"
 	"strings"
+	"time"
 )
 
 // Context entity: a thing in the NGSI model.
@@ -34,11 +35,12 @@ type Metadata struct {
 type AttributeType string
 
 const (
-	StringType     AttributeType = "String"
-	FloatType      AttributeType = "Float"
-	IntegerType    AttributeType = "Integer"
-	PercentageType AttributeType = "Percentage"
-	DateTimeType   AttributeType = "DateTime"
+	StringType          AttributeType = "String"
+	FloatType           AttributeType = "Float"
+	IntegerType         AttributeType = "Integer"
+	PercentageType      AttributeType = "Percentage"
+	DateTimeType        AttributeType = "DateTime"
+	StructuredValueType AttributeType = "StructuredValue"
 )
 
 type ActionType string
@@ -170,6 +172,59 @@ func (a *Attribute) GetAsFloat() (float64, error) {
 	return a.Value.(float64), nil
 }
 
+// SetTyped sets attribute name to value, using the codec registered for typ
+// to convert it to the raw JSON representation, if one is registered.
+func (e *Entity) SetTyped(name string, typ AttributeType, value interface{}) error {
+	raw := value
+	if codec, ok := codecFor(typ); ok {
+		converted, err := codec.Marshal(value)
+		if err != nil {
+			return err
+		}
+		raw = converted
+	}
+
+	e.Attributes[name] = &Attribute{
+		typeValue: typeValue{
+			Type:  typ,
+			Value: raw,
+		},
+	}
+	return nil
+}
+
+// GetTyped returns the attribute's value converted to its Go-native type
+// using the codec registered for the attribute's Type. With no codec
+// registered it returns the raw JSON value, same as Value.
+//
+// Conversion happens here rather than in UnmarshalJSON, so decoding an
+// entity never fails because one attribute holds a value a codec can't
+// convert; the error only surfaces when that attribute is actually read.
+func (a *Attribute) GetTyped() (interface{}, error) {
+	codec, ok := codecFor(a.Type)
+	if !ok {
+		return a.Value, nil
+	}
+	return codec.Unmarshal(a.Value)
+}
+
+// SetAttributeAsDateTime sets attribute name to a DateTime value.
+func (e *Entity) SetAttributeAsDateTime(name string, t time.Time) error {
+	return e.SetTyped(name, DateTimeType, t)
+}
+
+// GetAsDateTime returns the DateTime value of the attribute.
+func (a *Attribute) GetAsDateTime() (time.Time, error) {
+	if a.Type != DateTimeType {
+		return time.Time{}, fmt.Errorf("Attribute is not DateTime, but %s", a.Type)
+	}
+	v, err := a.GetTyped()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return v.(time.Time), nil
+}
+
 func NewBatchUpdate(action ActionType) *BatchUpdate {
 	b := &BatchUpdate{ActionType: action}
 	return b
@@ -0,0 +1,41 @@
+package model
+
+import "fmt"
+
+// SimpleQueryOperator is a comparison operator usable in a NGSI v2 simple
+// query expression (the `q` URL parameter).
+type SimpleQueryOperator string
+
+const (
+	SQEquals             SimpleQueryOperator = "=="
+	SQUnequals           SimpleQueryOperator = "!="
+	SQGreaterThan        SimpleQueryOperator = ">"
+	SQLessThan           SimpleQueryOperator = "<"
+	SQGreaterOrEqualThan SimpleQueryOperator = ">="
+	SQLessOrEqualThan    SimpleQueryOperator = "<="
+)
+
+// QueryStatement is a single element of a NGSI v2 query expression. It knows
+// how to render itself as part of the `q` URL parameter.
+type QueryStatement interface {
+	String() string
+}
+
+type binarySimpleQueryStatement struct {
+	attribute string
+	operator  SimpleQueryOperator
+	value     string
+}
+
+// NewBinarySimpleQueryStatement builds a statement comparing an attribute
+// against a value, e.g. `temperature>30`.
+func NewBinarySimpleQueryStatement(attribute string, operator SimpleQueryOperator, value string) (QueryStatement, error) {
+	if attribute == "" {
+		return nil, fmt.Errorf("attribute name cannot be empty")
+	}
+	return &binarySimpleQueryStatement{attribute: attribute, operator: operator, value: value}, nil
+}
+
+func (s *binarySimpleQueryStatement) String() string {
+	return fmt.Sprintf("%s%s%s", s.attribute, s.operator, s.value)
+}
@@ -0,0 +1,74 @@
+package model
+
+// Subscription describes a NGSI v2 subscription: a broker-side registration
+// of interest that triggers a notification whenever a matching entity
+// changes.
+type Subscription struct {
+	Id           string       `json:"id,omitempty"`
+	Description  string       `json:"description,omitempty"`
+	Subject      Subject      `json:"subject"`
+	Notification Notification `json:"notification"`
+	Expires      string       `json:"expires,omitempty"`
+	Status       string       `json:"status,omitempty"`
+	Throttling   int          `json:"throttling,omitempty"`
+}
+
+// Subject describes what a subscription is about: the entities to watch and
+// the condition that triggers a notification.
+type Subject struct {
+	Entities  []SubjectEntity `json:"entities"`
+	Condition Condition       `json:"condition"`
+}
+
+// SubjectEntity identifies, or pattern-matches, the entities a subscription
+// watches.
+type SubjectEntity struct {
+	Id        string `json:"id,omitempty"`
+	IdPattern string `json:"idPattern,omitempty"`
+	Type      string `json:"type,omitempty"`
+}
+
+// Condition lists the attributes whose change triggers a notification, plus
+// an optional filtering expression.
+type Condition struct {
+	Attrs      []string    `json:"attrs,omitempty"`
+	Expression *Expression `json:"expression,omitempty"`
+}
+
+// Expression is a server-side filter evaluated against the attributes named
+// in Condition.Attrs, using the same operators as the `q`/geo-query URL
+// parameters.
+type Expression struct {
+	Q        string `json:"q,omitempty"`
+	Mq       string `json:"mq,omitempty"`
+	Georel   string `json:"georel,omitempty"`
+	Geometry string `json:"geometry,omitempty"`
+	Coords   string `json:"coords,omitempty"`
+}
+
+// Notification describes where and how a subscription's notifications are
+// delivered.
+type Notification struct {
+	Http        *HttpParams `json:"http,omitempty"`
+	Attrs       []string    `json:"attrs,omitempty"`
+	ExceptAttrs []string    `json:"exceptAttrs,omitempty"`
+	AttrsFormat string      `json:"attrsFormat,omitempty"`
+}
+
+// HttpParams configures a plain HTTP notification callback.
+type HttpParams struct {
+	Url string `json:"url"`
+}
+
+// NewSubscription creates a subscription notifying callbackUrl whenever an
+// entity of entityType changes.
+func NewSubscription(entityType, callbackUrl string) *Subscription {
+	return &Subscription{
+		Subject: Subject{
+			Entities: []SubjectEntity{{Type: entityType, IdPattern: ".*"}},
+		},
+		Notification: Notification{
+			Http: &HttpParams{Url: callbackUrl},
+		},
+	}
+}
@@ -0,0 +1,58 @@
+package notify
+
+import (
+	"sync"
+
+	"github.com/phoops/ngsiv2/model"
+)
+
+// Event is a single notification fanned out to Hub subscribers.
+type Event struct {
+	SubscriptionId string
+	Entities       []*model.Entity
+}
+
+// Hub lets multiple goroutines consume the same notification stream without
+// racing on the Receiver's handler map, similar to an SSE broker.
+type Hub struct {
+	mu   sync.Mutex
+	subs map[chan Event]struct{}
+}
+
+// NewHub creates an empty Hub.
+func NewHub() *Hub {
+	return &Hub{subs: make(map[chan Event]struct{})}
+}
+
+// Subscribe returns a channel receiving every Event published to the hub.
+// Call the returned func to unsubscribe and release the channel.
+func (h *Hub) Subscribe() (<-chan Event, func()) {
+	ch := make(chan Event, 16)
+
+	h.mu.Lock()
+	h.subs[ch] = struct{}{}
+	h.mu.Unlock()
+
+	unsubscribe := func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		if _, ok := h.subs[ch]; ok {
+			delete(h.subs, ch)
+			close(ch)
+		}
+	}
+	return ch, unsubscribe
+}
+
+// publish fans e out to every current subscriber, dropping it for a
+// subscriber whose buffer is full rather than blocking the receiver.
+func (h *Hub) publish(e Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for ch := range h.subs {
+		select {
+		case ch <- e:
+		default:
+		}
+	}
+}
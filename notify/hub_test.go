@@ -0,0 +1,50 @@
+package notify_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/phoops/ngsiv2/notify"
+)
+
+func TestHubFansOutToMultipleSubscribers(t *testing.T) {
+	r := notify.NewReceiver()
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	ch1, unsubscribe1 := r.Hub().Subscribe()
+	defer unsubscribe1()
+	ch2, unsubscribe2 := r.Hub().Subscribe()
+	defer unsubscribe2()
+
+	res, err := http.Post(ts.URL, "application/json", strings.NewReader(`{"subscriptionId":"sub1","data":[{"id":"r1","type":"Room"}]}`))
+	if err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+	defer res.Body.Close()
+
+	for i, ch := range []<-chan notify.Event{ch1, ch2} {
+		select {
+		case e := <-ch:
+			if e.SubscriptionId != "sub1" || len(e.Entities) != 1 || e.Entities[0].Id != "r1" {
+				t.Fatalf("Subscriber %d got unexpected event: %+v", i, e)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("Subscriber %d never received the event", i)
+		}
+	}
+}
+
+func TestHubUnsubscribeClosesChannel(t *testing.T) {
+	h := notify.NewHub()
+	ch, unsubscribe := h.Subscribe()
+
+	unsubscribe()
+
+	if _, ok := <-ch; ok {
+		t.Fatal("Expected channel to be closed after unsubscribe")
+	}
+}
@@ -0,0 +1,88 @@
+// Package notify implements a NGSI v2 notification receiver: an HTTP
+// endpoint that accepts subscription callback payloads and dispatches them
+// to handlers registered per subscription.
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/phoops/ngsiv2/model"
+)
+
+// Handler processes the entities delivered by a single notification.
+type Handler func(ctx context.Context, entities []*model.Entity) error
+
+// payload mirrors the body a context broker POSTs to a subscription's
+// callback URL.
+type payload struct {
+	SubscriptionId string          `json:"subscriptionId"`
+	Data           []*model.Entity `json:"data"`
+}
+
+// Receiver is an http.Handler that decodes NGSI v2 notifications and
+// dispatches them to handlers registered per subscription ID.
+type Receiver struct {
+	mu       sync.RWMutex
+	handlers map[string]Handler
+	hub      *Hub
+}
+
+// NewReceiver creates an empty Receiver.
+func NewReceiver() *Receiver {
+	return &Receiver{
+		handlers: make(map[string]Handler),
+		hub:      NewHub(),
+	}
+}
+
+// Handle registers the handler invoked for notifications belonging to subID,
+// replacing any handler previously registered for it.
+func (r *Receiver) Handle(subID string, h Handler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.handlers[subID] = h
+}
+
+// Hub returns the fan-out hub every notification is published to, regardless
+// of whether a per-subscription handler is registered for it. Use it when
+// several goroutines need to consume the same event stream concurrently.
+func (r *Receiver) Hub() *Hub {
+	return r.hub
+}
+
+// ServeHTTP implements http.Handler.
+func (r *Receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	defer req.Body.Close()
+
+	p := payload{}
+	if err := json.NewDecoder(req.Body).Decode(&p); err != nil {
+		http.Error(w, fmt.Sprintf("invalid notification payload: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	r.hub.publish(Event{SubscriptionId: p.SubscriptionId, Entities: p.Data})
+
+	r.mu.RLock()
+	h, ok := r.handlers[p.SubscriptionId]
+	r.mu.RUnlock()
+
+	if ok {
+		if err := h(req.Context(), p.Data); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListenAndServe starts an http.Server exposing the Receiver at path.
+func (r *Receiver) ListenAndServe(addr, path string) error {
+	mux := http.NewServeMux()
+	mux.Handle(path, r)
+	return http.ListenAndServe(addr, mux)
+}
@@ -0,0 +1,44 @@
+package notify_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/phoops/ngsiv2/model"
+	"github.com/phoops/ngsiv2/notify"
+)
+
+func TestReceiverDispatchesToHandler(t *testing.T) {
+	r := notify.NewReceiver()
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	received := make(chan []*model.Entity, 1)
+	r.Handle("sub1", func(ctx context.Context, entities []*model.Entity) error {
+		received <- entities
+		return nil
+	})
+
+	res, err := http.Post(ts.URL, "application/json", strings.NewReader(`{"subscriptionId":"sub1","data":[{"id":"r1","type":"Room"}]}`))
+	if err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusNoContent {
+		t.Fatalf("Expected 204, got %d", res.StatusCode)
+	}
+
+	select {
+	case entities := <-received:
+		if len(entities) != 1 || entities[0].Id != "r1" {
+			t.Fatal("Invalid entities dispatched to handler")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Handler was never called")
+	}
+}
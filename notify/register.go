@@ -0,0 +1,24 @@
+package notify
+
+import (
+	"context"
+
+	"github.com/phoops/ngsiv2/client"
+	"github.com/phoops/ngsiv2/model"
+)
+
+// RegisterSubscription creates a subscription on cli that notifies this
+// receiver's public URL (publicUrl+path, typically reachable through a
+// reverse proxy or tunnel in front of ListenAndServe) whenever an entity of
+// entityType changes, and wires h up to handle the resulting notifications.
+func (r *Receiver) RegisterSubscription(ctx context.Context, cli *client.NgsiV2Client, publicUrl, path, entityType string, h Handler) (string, error) {
+	sub := model.NewSubscription(entityType, publicUrl+path)
+
+	subID, err := cli.CreateSubscriptionCtx(ctx, sub)
+	if err != nil {
+		return "", err
+	}
+
+	r.Handle(subID, h)
+	return subID, nil
+}
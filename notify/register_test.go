@@ -0,0 +1,69 @@
+package notify_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/phoops/ngsiv2/client"
+	"github.com/phoops/ngsiv2/model"
+	"github.com/phoops/ngsiv2/notify"
+)
+
+func TestRegisterSubscription(t *testing.T) {
+	var reqPath, reqBody string
+	broker := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				reqPath = r.URL.Path
+				b, _ := ioutil.ReadAll(r.Body)
+				reqBody = string(b)
+				w.Header().Set("Location", "/v2/subscriptions/sub1")
+				w.WriteHeader(http.StatusCreated)
+			}))
+	defer broker.Close()
+
+	cli, err := client.NewNgsiV2Client(client.SetUrl(broker.URL))
+	if err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+
+	r := notify.NewReceiver()
+	receiverServer := httptest.NewServer(r)
+	defer receiverServer.Close()
+
+	called := make(chan struct{}, 1)
+	id, err := r.RegisterSubscription(context.Background(), cli, "http://receiver.example", "/notify", "Room",
+		func(ctx context.Context, entities []*model.Entity) error {
+			called <- struct{}{}
+			return nil
+		})
+	if err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+	if id != "sub1" {
+		t.Fatalf("Expected 'sub1', got '%s'", id)
+	}
+	if reqPath != "/v2/subscriptions" {
+		t.Fatalf("Expected POST to /v2/subscriptions, got '%s'", reqPath)
+	}
+	if !strings.Contains(reqBody, "http://receiver.example/notify") || !strings.Contains(reqBody, `"type":"Room"`) {
+		t.Fatalf("Subscription body missing callback url or entity type: %s", reqBody)
+	}
+
+	res, err := http.Post(receiverServer.URL, "application/json", strings.NewReader(`{"subscriptionId":"sub1","data":[{"id":"r1","type":"Room"}]}`))
+	if err != nil {
+		t.Fatalf("Unexpected error: '%v'", err)
+	}
+	defer res.Body.Close()
+
+	select {
+	case <-called:
+	case <-time.After(time.Second):
+		t.Fatal("Handler registered by RegisterSubscription was never called")
+	}
+}